@@ -0,0 +1,34 @@
+package datacap
+
+import (
+	"github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/ipfs/go-cid"
+
+	"github.com/filecoin-project/lily/chain/actors"
+	"github.com/filecoin-project/lotus/chain/actors/adt"
+)
+
+// State is the version-independent view over the datacap actor used by
+// lily's extractors. v9 models the actor as an FRC-46 fungible token, so
+// alongside the legacy verified-client balances it also exposes the
+// token's operator allowances.
+type State interface {
+	Code() cid.Cid
+	ActorKey() string
+	ActorVersion() actors.Version
+
+	Governor() (address.Address, error)
+
+	VerifiedClients() (adt.Map, error)
+	VerifiedClientDataCap(address.Address) (bool, abi.StoragePower, error)
+	VerifiedClientsMapBitWidth() int
+	VerifiedClientsMapHashFunction() func(input []byte) []byte
+	ForEachClient(cb func(addr address.Address, dcap abi.StoragePower) error) error
+
+	// ForEachAllowance walks the actor's owner -> operator -> allowance
+	// map, invoking cb for every approved allowance it finds.
+	ForEachAllowance(cb func(owner, operator address.Address, allowance abi.TokenAmount) error) error
+
+	GetState() interface{}
+}
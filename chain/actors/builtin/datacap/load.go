@@ -0,0 +1,28 @@
+package datacap
+
+import (
+	"golang.org/x/xerrors"
+
+	"github.com/filecoin-project/lotus/chain/actors/adt"
+	"github.com/filecoin-project/lotus/chain/types"
+
+	"github.com/filecoin-project/lily/chain/actors"
+)
+
+// Load resolves the actor's code CID to the appropriate versioned State
+// implementation. The datacap actor was introduced in actors v9, so there
+// is only one version to dispatch to today.
+func Load(store adt.Store, act *types.Actor) (State, error) {
+	if name, av, ok := actors.GetActorMetaByCode(act.Code); ok {
+		if name != actors.DatacapKey {
+			return nil, xerrors.Errorf("actor code is not datacap: %s", name)
+		}
+
+		switch av {
+		case actors.Version9:
+			return load9(store, act.Head)
+		}
+	}
+
+	return nil, xerrors.Errorf("unknown actor code %s", act.Code)
+}
@@ -0,0 +1,38 @@
+package datacap
+
+// Methods enumerates the datacap actor's exported method numbers. These
+// are stable across actor versions; only the params/return CBOR layout
+// changes between them.
+var Methods = struct {
+	Constructor       uint64
+	Mint              uint64
+	Destroy           uint64
+	Name              uint64
+	Symbol            uint64
+	TotalSupply       uint64
+	BalanceOf         uint64
+	Transfer          uint64
+	TransferFrom      uint64
+	IncreaseAllowance uint64
+	DecreaseAllowance uint64
+	RevokeAllowance   uint64
+	Burn              uint64
+	BurnFrom          uint64
+	Allowance         uint64
+}{
+	Constructor:       1,
+	Mint:              2,
+	Destroy:           3,
+	Name:              4,
+	Symbol:            5,
+	TotalSupply:       6,
+	BalanceOf:         7,
+	Transfer:          8,
+	TransferFrom:      9,
+	IncreaseAllowance: 10,
+	DecreaseAllowance: 11,
+	RevokeAllowance:   12,
+	Burn:              13,
+	BurnFrom:          14,
+	Allowance:         15,
+}
@@ -7,6 +7,8 @@ import (
 	"github.com/filecoin-project/go-address"
 	"github.com/filecoin-project/go-state-types/abi"
 	"github.com/ipfs/go-cid"
+	cbg "github.com/whyrusleeping/cbor-gen"
+	"golang.org/x/xerrors"
 
 	"github.com/filecoin-project/lily/chain/actors"
 	"github.com/filecoin-project/lotus/chain/actors/adt"
@@ -74,6 +76,50 @@ func (s *state9) VerifiedClientsMapHashFunction() func(input []byte) []byte {
 	}
 }
 
+// ForEachAllowance walks the allowances HAMT backing the v9 datacap
+// actor's approve/transferFrom semantics - an owner-keyed HAMT of
+// operator-keyed HAMTs of abi.TokenAmount - invoking cb for every
+// (owner, operator, allowance) triple it finds.
+func (s *state9) ForEachAllowance(cb func(owner, operator address.Address, allowance abi.TokenAmount) error) error {
+	// like the balances HAMT consulted by VerifiedClients/ForEachClient,
+	// allowances are keyed by actor-ID (abi.IdAddrKey), not serialized
+	// address bytes.
+	outer, err := adt9.AsMap(s.store, s.Token.Allowances, int(s.Token.HamtBitWidth))
+	if err != nil {
+		return xerrors.Errorf("loading allowances map: %w", err)
+	}
+
+	var innerRoot cbg.CborCid
+	return outer.ForEach(&innerRoot, func(ownerKey string) error {
+		ownerID, err := abi.ParseUIntKey(ownerKey)
+		if err != nil {
+			return xerrors.Errorf("parsing owner id: %w", err)
+		}
+		owner, err := address.NewIDAddress(ownerID)
+		if err != nil {
+			return xerrors.Errorf("building owner address: %w", err)
+		}
+
+		inner, err := adt9.AsMap(s.store, cid.Cid(innerRoot), int(s.Token.HamtBitWidth))
+		if err != nil {
+			return xerrors.Errorf("loading allowances for %s: %w", owner, err)
+		}
+
+		var allowance abi.TokenAmount
+		return inner.ForEach(&allowance, func(operatorKey string) error {
+			operatorID, err := abi.ParseUIntKey(operatorKey)
+			if err != nil {
+				return xerrors.Errorf("parsing operator id: %w", err)
+			}
+			operator, err := address.NewIDAddress(operatorID)
+			if err != nil {
+				return xerrors.Errorf("building operator address: %w", err)
+			}
+			return cb(owner, operator, allowance)
+		})
+	})
+}
+
 func (s *state9) ActorKey() string {
 	return actors.DatacapKey
 }
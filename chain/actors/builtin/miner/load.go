@@ -0,0 +1,38 @@
+package miner
+
+import (
+	"golang.org/x/xerrors"
+
+	"github.com/filecoin-project/lotus/chain/actors/adt"
+	"github.com/filecoin-project/lotus/chain/types"
+
+	"github.com/filecoin-project/lily/chain/actors"
+)
+
+// Load resolves the actor's code CID to the appropriate versioned State
+// implementation.
+func Load(store adt.Store, act *types.Actor) (State, error) {
+	name, av, ok := actors.GetActorMetaByCode(act.Code)
+	if !ok {
+		return nil, xerrors.Errorf("unknown actor code %s", act.Code)
+	}
+	if name != actors.MinerKey {
+		return nil, xerrors.Errorf("actor code is not miner: %s", name)
+	}
+
+	switch av {
+	case actors.Version0:
+		return load0(store, act.Head)
+	case actors.Version9:
+		return load9(store, act.Head)
+	default:
+		// Each actor version ships its own independently-generated
+		// deadline/partition CBOR layout in go-state-types - e.g.
+		// Partition.Unproven was only added in a later version than v2 -
+		// so decoding a v1-v8 deadline/partition AMT entry with the v9
+		// structs either fails CBOR unmarshal or silently misaligns
+		// fields. Fail loudly instead of guessing until this version
+		// gets its own State implementation.
+		return nil, xerrors.Errorf("unsupported miner actor version %d: no version-specific state loader implemented", av)
+	}
+}
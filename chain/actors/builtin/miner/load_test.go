@@ -0,0 +1,22 @@
+package miner
+
+import (
+	"testing"
+
+	"github.com/filecoin-project/lotus/chain/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/filecoin-project/lily/chain/actors"
+)
+
+// TestLoadRejectsVersionsWithoutALoader guards against Load silently
+// routing an actor version it has no decoder for to the wrong one - the
+// bug that previously sent every non-v0 version through load9.
+func TestLoadRejectsVersionsWithoutALoader(t *testing.T) {
+	code, ok := actors.GetActorCodeID(actors.Version2, actors.MinerKey)
+	require.True(t, ok)
+
+	_, err := Load(nil, &types.Actor{Code: code})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "unsupported miner actor version")
+}
@@ -0,0 +1,89 @@
+package miner
+
+import (
+	"github.com/filecoin-project/go-bitfield"
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/filecoin-project/go-state-types/dline"
+	"github.com/filecoin-project/specs-actors/v7/actors/runtime/proof"
+	"github.com/ipfs/go-cid"
+
+	"github.com/filecoin-project/lily/chain/actors"
+)
+
+// State is the version-independent view over the miner actor used by
+// lily's extractors. Deadline/partition layouts diverge between actors
+// v0-v9, so callers always go through this abstraction rather than an
+// actor-version-specific state struct.
+type State interface {
+	Code() cid.Cid
+	ActorKey() string
+	ActorVersion() actors.Version
+
+	DeadlineInfo(epoch abi.ChainEpoch) (*dline.Info, error)
+	LoadDeadline(idx uint64) (Deadline, error)
+	ForEachDeadline(cb func(idx uint64, dl Deadline) error) error
+
+	// DecodeSubmitWindowedPoStParams decodes a SubmitWindowedPoSt
+	// message's raw CBOR params using the layout this actor version
+	// expects, normalizing the result to the common
+	// SubmitWindowedPoStParams shape.
+	DecodeSubmitWindowedPoStParams(raw []byte) (SubmitWindowedPoStParams, error)
+
+	GetState() interface{}
+}
+
+type Deadline interface {
+	DisputableProofCount() (uint64, error)
+	LiveSectors() (uint64, error)
+	TotalSectors() (uint64, error)
+	FaultyPower() (abi.StoragePower, error)
+	LoadPartition(idx uint64) (Partition, error)
+	ForEachPartition(cb func(idx uint64, p Partition) error) error
+
+	// LoadProofPartitions returns the partition indices covered by the
+	// deadline's optimistically-accepted PoSt submission at postIndex, as
+	// recorded in the deadline's snapshot at the time that PoSt was
+	// submitted. DisputeWindowedPoSt only invalidates that one
+	// submission, so a dispute only ever affects these partitions, not
+	// every partition in the deadline.
+	LoadProofPartitions(postIndex uint64) (bitfield.BitField, error)
+}
+
+// Partition deliberately has no RecoveredSectors accessor: a point-in-time
+// snapshot can't express "recovered" (a fully-recovered sector is simply
+// absent from both Faults and Recoveries, indistinguishable from a sector
+// that was never faulty). Recovered sectors are instead recorded as
+// MinerSectorEvent rows by diffing RecoveringSectors against the skipped
+// set of each SubmitWindowedPoSt message as it's processed.
+type Partition interface {
+	AllSectors() (bitfield.BitField, error)
+	FaultySectors() (bitfield.BitField, error)
+	RecoveringSectors() (bitfield.BitField, error)
+	TerminatedSectors() (bitfield.BitField, error)
+	UnprovenSectors() (bitfield.BitField, error)
+}
+
+// SubmitWindowedPoStParams is the actor-version-independent shape of a
+// SubmitWindowedPoSt message's params. Method 5 is stable across actor
+// versions, but its params CBOR layout is not - see
+// DecodeSubmitWindowedPoStParams.
+type SubmitWindowedPoStParams struct {
+	Deadline         uint64
+	Partitions       []PoStPartition
+	Proofs           []proof.PoStProof
+	ChainCommitEpoch abi.ChainEpoch
+	ChainCommitRand  abi.Randomness
+}
+
+type PoStPartition struct {
+	Index   uint64
+	Skipped bitfield.BitField
+}
+
+// DisputeWindowedPoStParams is method 24's params. The layout has been
+// stable since it was introduced, so unlike SubmitWindowedPoStParams it
+// does not need per-version decoding.
+type DisputeWindowedPoStParams struct {
+	Deadline  uint64
+	PoStIndex uint64
+}
@@ -0,0 +1,181 @@
+package miner
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/filecoin-project/go-bitfield"
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/filecoin-project/go-state-types/dline"
+	"github.com/filecoin-project/lotus/chain/actors/adt"
+	miner0 "github.com/filecoin-project/specs-actors/actors/builtin/miner"
+	"github.com/ipfs/go-cid"
+	"golang.org/x/xerrors"
+
+	"github.com/filecoin-project/lily/chain/actors"
+)
+
+var _ State = (*state0)(nil)
+
+func load0(store adt.Store, root cid.Cid) (State, error) {
+	out := state0{store: store}
+	if err := store.Get(store.Context(), root, &out.State); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+type state0 struct {
+	miner0.State
+	store adt.Store
+}
+
+func (s *state0) GetState() interface{} {
+	return &s.State
+}
+
+func (s *state0) ActorKey() string {
+	return actors.MinerKey
+}
+
+func (s *state0) ActorVersion() actors.Version {
+	return actors.Version0
+}
+
+func (s *state0) Code() cid.Cid {
+	code, ok := actors.GetActorCodeID(s.ActorVersion(), s.ActorKey())
+	if !ok {
+		panic(fmt.Errorf("didn't find actor %v code id for actor version %d", s.ActorKey(), s.ActorVersion()))
+	}
+	return code
+}
+
+func (s *state0) DeadlineInfo(epoch abi.ChainEpoch) (*dline.Info, error) {
+	info := s.State.DeadlineInfo(epoch)
+	return &dline.Info{
+		CurrentEpoch: info.CurrentEpoch,
+		PeriodStart:  info.PeriodStart,
+		Index:        info.Index,
+		Open:         info.Open,
+		Close:        info.Close,
+		Challenge:    info.Challenge,
+		FaultCutoff:  info.FaultCutoff,
+	}, nil
+}
+
+func (s *state0) LoadDeadline(idx uint64) (Deadline, error) {
+	dls, err := s.State.LoadDeadlines(s.store)
+	if err != nil {
+		return nil, xerrors.Errorf("loading deadlines: %w", err)
+	}
+	dl, err := dls.LoadDeadline(s.store, idx)
+	if err != nil {
+		return nil, xerrors.Errorf("loading deadline %d: %w", idx, err)
+	}
+	return &deadline0{dl, s.store}, nil
+}
+
+func (s *state0) ForEachDeadline(cb func(idx uint64, dl Deadline) error) error {
+	dls, err := s.State.LoadDeadlines(s.store)
+	if err != nil {
+		return xerrors.Errorf("loading deadlines: %w", err)
+	}
+	return dls.ForEach(s.store, func(idx uint64, dl *miner0.Deadline) error {
+		return cb(idx, &deadline0{dl, s.store})
+	})
+}
+
+// DecodeSubmitWindowedPoStParams decodes the actors v0 SubmitWindowedPoSt
+// params layout, which predates the ChainCommitEpoch/ChainCommitRand
+// anti-spam fields carried by every later version.
+func (s *state0) DecodeSubmitWindowedPoStParams(raw []byte) (SubmitWindowedPoStParams, error) {
+	var params miner0.SubmitWindowedPoStParams
+	if err := params.UnmarshalCBOR(bytes.NewBuffer(raw)); err != nil {
+		return SubmitWindowedPoStParams{}, xerrors.Errorf("unmarshal v0 submit windowed post params: %w", err)
+	}
+
+	out := SubmitWindowedPoStParams{
+		Deadline: params.Deadline,
+		Proofs:   params.Proofs,
+	}
+	for _, p := range params.Partitions {
+		out.Partitions = append(out.Partitions, PoStPartition{Index: p.Index, Skipped: p.Skipped})
+	}
+	return out, nil
+}
+
+type deadline0 struct {
+	*miner0.Deadline
+	store adt.Store
+}
+
+// Actors v0 predates DisputableProofCount; optimistic PoSt acceptance
+// was only introduced with nv12, so there is nothing to dispute here.
+func (d *deadline0) DisputableProofCount() (uint64, error) {
+	return 0, nil
+}
+
+func (d *deadline0) LiveSectors() (uint64, error) {
+	return d.Deadline.LiveSectors, nil
+}
+
+func (d *deadline0) TotalSectors() (uint64, error) {
+	return d.Deadline.TotalSectors, nil
+}
+
+func (d *deadline0) FaultyPower() (abi.StoragePower, error) {
+	return d.Deadline.FaultyPower.Raw, nil
+}
+
+func (d *deadline0) LoadPartition(idx uint64) (Partition, error) {
+	p, err := d.Deadline.LoadPartition(d.store, idx)
+	if err != nil {
+		return nil, err
+	}
+	return &partition0{p}, nil
+}
+
+func (d *deadline0) ForEachPartition(cb func(idx uint64, p Partition) error) error {
+	parts, err := adt.AsArray(d.store, d.Deadline.Partitions, miner0.DeadlinePartitionsAmtBitwidth)
+	if err != nil {
+		return xerrors.Errorf("loading partitions: %w", err)
+	}
+	var part miner0.Partition
+	return parts.ForEach(&part, func(idx int64) error {
+		cp := part
+		return cb(uint64(idx), &partition0{&cp})
+	})
+}
+
+// Actors v0 predates LoadProofPartitions; optimistic PoSt acceptance was
+// only introduced with nv12 (actors v7), so there is no submission here
+// for a dispute to resolve.
+func (d *deadline0) LoadProofPartitions(postIndex uint64) (bitfield.BitField, error) {
+	return bitfield.BitField{}, xerrors.Errorf("actors v0 has no optimistic post submissions to dispute")
+}
+
+type partition0 struct {
+	*miner0.Partition
+}
+
+func (p *partition0) AllSectors() (bitfield.BitField, error) {
+	return p.Partition.Sectors, nil
+}
+
+func (p *partition0) FaultySectors() (bitfield.BitField, error) {
+	return p.Partition.Faults, nil
+}
+
+func (p *partition0) RecoveringSectors() (bitfield.BitField, error) {
+	return p.Partition.Recoveries, nil
+}
+
+func (p *partition0) TerminatedSectors() (bitfield.BitField, error) {
+	return p.Partition.Terminated, nil
+}
+
+// Actors v0's partition layout predates UnprovenSectors; every sector in
+// an active partition is treated as already due for proof.
+func (p *partition0) UnprovenSectors() (bitfield.BitField, error) {
+	return bitfield.NewFromSet(nil), nil
+}
@@ -0,0 +1,179 @@
+package miner
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/filecoin-project/go-bitfield"
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/filecoin-project/go-state-types/dline"
+	"github.com/filecoin-project/lotus/chain/actors/adt"
+	"github.com/ipfs/go-cid"
+	"golang.org/x/xerrors"
+
+	miner9 "github.com/filecoin-project/go-state-types/builtin/v9/miner"
+
+	"github.com/filecoin-project/lily/chain/actors"
+)
+
+var _ State = (*state9)(nil)
+
+func load9(store adt.Store, root cid.Cid) (State, error) {
+	out := state9{store: store}
+	if err := store.Get(store.Context(), root, &out.State); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+type state9 struct {
+	miner9.State
+	store adt.Store
+}
+
+func (s *state9) GetState() interface{} {
+	return &s.State
+}
+
+func (s *state9) ActorKey() string {
+	return actors.MinerKey
+}
+
+func (s *state9) ActorVersion() actors.Version {
+	return actors.Version9
+}
+
+func (s *state9) Code() cid.Cid {
+	code, ok := actors.GetActorCodeID(s.ActorVersion(), s.ActorKey())
+	if !ok {
+		panic(fmt.Errorf("didn't find actor %v code id for actor version %d", s.ActorKey(), s.ActorVersion()))
+	}
+	return code
+}
+
+func (s *state9) DeadlineInfo(epoch abi.ChainEpoch) (*dline.Info, error) {
+	return s.State.DeadlineInfo(epoch), nil
+}
+
+func (s *state9) LoadDeadline(idx uint64) (Deadline, error) {
+	dls, err := s.State.LoadDeadlines(s.store)
+	if err != nil {
+		return nil, xerrors.Errorf("loading deadlines: %w", err)
+	}
+	dl, err := dls.LoadDeadline(s.store, idx)
+	if err != nil {
+		return nil, xerrors.Errorf("loading deadline %d: %w", idx, err)
+	}
+	return &deadline9{dl, s.store}, nil
+}
+
+func (s *state9) ForEachDeadline(cb func(idx uint64, dl Deadline) error) error {
+	dls, err := s.State.LoadDeadlines(s.store)
+	if err != nil {
+		return xerrors.Errorf("loading deadlines: %w", err)
+	}
+	return dls.ForEach(s.store, func(idx uint64, dl *miner9.Deadline) error {
+		return cb(idx, &deadline9{dl, s.store})
+	})
+}
+
+// DecodeSubmitWindowedPoStParams decodes the v9 SubmitWindowedPoSt params
+// layout, including its ChainCommitEpoch/ChainCommitRand fields.
+func (s *state9) DecodeSubmitWindowedPoStParams(raw []byte) (SubmitWindowedPoStParams, error) {
+	var params miner9.SubmitWindowedPoStParams
+	if err := params.UnmarshalCBOR(bytes.NewBuffer(raw)); err != nil {
+		return SubmitWindowedPoStParams{}, xerrors.Errorf("unmarshal v9 submit windowed post params: %w", err)
+	}
+
+	out := SubmitWindowedPoStParams{
+		Deadline:         params.Deadline,
+		Proofs:           params.Proofs,
+		ChainCommitEpoch: params.ChainCommitEpoch,
+		ChainCommitRand:  params.ChainCommitRand,
+	}
+	for _, p := range params.Partitions {
+		out.Partitions = append(out.Partitions, PoStPartition{Index: p.Index, Skipped: p.Skipped})
+	}
+	return out, nil
+}
+
+type deadline9 struct {
+	*miner9.Deadline
+	store adt.Store
+}
+
+func (d *deadline9) DisputableProofCount() (uint64, error) {
+	return d.Deadline.DisputableProofCount, nil
+}
+
+func (d *deadline9) LiveSectors() (uint64, error) {
+	return d.Deadline.LiveSectors, nil
+}
+
+func (d *deadline9) TotalSectors() (uint64, error) {
+	return d.Deadline.TotalSectors, nil
+}
+
+func (d *deadline9) FaultyPower() (abi.StoragePower, error) {
+	return d.Deadline.FaultyPower.Raw, nil
+}
+
+func (d *deadline9) LoadPartition(idx uint64) (Partition, error) {
+	p, err := d.Deadline.LoadPartition(d.store, idx)
+	if err != nil {
+		return nil, err
+	}
+	return &partition9{p}, nil
+}
+
+func (d *deadline9) ForEachPartition(cb func(idx uint64, p Partition) error) error {
+	parts, err := adt.AsArray(d.store, d.Deadline.Partitions, miner9.DeadlinePartitionsAmtBitwidth)
+	if err != nil {
+		return xerrors.Errorf("loading partitions: %w", err)
+	}
+	var part miner9.Partition
+	return parts.ForEach(&part, func(idx int64) error {
+		cp := part
+		return cb(uint64(idx), &partition9{&cp})
+	})
+}
+
+func (d *deadline9) LoadProofPartitions(postIndex uint64) (bitfield.BitField, error) {
+	submissions, err := adt.AsArray(d.store, d.Deadline.OptimisticPoStSubmissionsSnapshot, miner9.DeadlineOptimisticPoStSubmissionsAmtBitwidth)
+	if err != nil {
+		return bitfield.BitField{}, xerrors.Errorf("loading optimistic post submissions snapshot: %w", err)
+	}
+	var post miner9.WindowedPoSt
+	found, err := submissions.Get(postIndex, &post)
+	if err != nil {
+		return bitfield.BitField{}, xerrors.Errorf("loading post submission %d: %w", postIndex, err)
+	}
+	if !found {
+		return bitfield.BitField{}, xerrors.Errorf("no post submission at index %d", postIndex)
+	}
+	return post.Partitions, nil
+}
+
+type partition9 struct {
+	*miner9.Partition
+}
+
+func (p *partition9) AllSectors() (bitfield.BitField, error) {
+	return p.Partition.Sectors, nil
+}
+
+func (p *partition9) FaultySectors() (bitfield.BitField, error) {
+	return p.Partition.Faults, nil
+}
+
+func (p *partition9) RecoveringSectors() (bitfield.BitField, error) {
+	return p.Partition.Recoveries, nil
+}
+
+func (p *partition9) TerminatedSectors() (bitfield.BitField, error) {
+	return p.Partition.Terminated, nil
+}
+
+func (p *partition9) UnprovenSectors() (bitfield.BitField, error) {
+	return p.Partition.Unproven, nil
+}
@@ -0,0 +1,34 @@
+package datacap
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+
+	"github.com/filecoin-project/lily/model"
+)
+
+// DatacapAllowance is a point-in-time snapshot of one operator's
+// approved spending allowance over an owner's datacap balance, diffed
+// against the previous tipset so only changed allowances are persisted.
+type DatacapAllowance struct {
+	Height    int64  `pg:",pk,notnull,use_zero"`
+	Owner     string `pg:",pk,notnull"`
+	Operator  string `pg:",pk,notnull"`
+	Allowance string `pg:",notnull"`
+}
+
+func (da *DatacapAllowance) AsModel() *DatacapAllowance {
+	return da
+}
+
+type DatacapAllowanceList []*DatacapAllowance
+
+func (dl DatacapAllowanceList) Persist(ctx context.Context, s model.StorageBatch) error {
+	if len(dl) == 0 {
+		return nil
+	}
+	ctx, span := otel.Tracer("").Start(ctx, "DatacapAllowanceList.Persist")
+	defer span.End()
+	return s.PersistModel(ctx, dl)
+}
@@ -0,0 +1,51 @@
+package datacap
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+
+	"github.com/filecoin-project/lily/model"
+)
+
+// DatacapTokenEvent records a single balance-affecting message sent to
+// the v9 datacap actor's token interface, making the FRC-46 token-flow
+// layer of FIP-0045 queryable instead of only visible in raw balance
+// snapshots.
+type DatacapTokenEvent struct {
+	Height     int64  `pg:",pk,notnull,use_zero"`
+	MessageCID string `pg:",pk,notnull"`
+	Event      string `pg:",notnull"`
+	From       string `pg:",notnull"`
+	To         string `pg:",notnull"`
+	Operator   string `pg:",use_zero"`
+	Amount     string `pg:",notnull"`
+	ExitCode   int64  `pg:",notnull,use_zero"`
+}
+
+func (e *DatacapTokenEvent) AsModel() *DatacapTokenEvent {
+	return e
+}
+
+type DatacapTokenEventList []*DatacapTokenEvent
+
+func (el DatacapTokenEventList) Persist(ctx context.Context, s model.StorageBatch) error {
+	if len(el) == 0 {
+		return nil
+	}
+	ctx, span := otel.Tracer("").Start(ctx, "DatacapTokenEventList.Persist")
+	defer span.End()
+	return s.PersistModel(ctx, el)
+}
+
+const (
+	DatacapEventTransfer          = "TRANSFER"
+	DatacapEventTransferFrom      = "TRANSFER_FROM"
+	DatacapEventIncreaseAllowance = "INCREASE_ALLOWANCE"
+	DatacapEventDecreaseAllowance = "DECREASE_ALLOWANCE"
+	DatacapEventRevokeAllowance   = "REVOKE_ALLOWANCE"
+	DatacapEventBurn              = "BURN"
+	DatacapEventBurnFrom          = "BURN_FROM"
+	DatacapEventMint              = "MINT"
+	DatacapEventDestroy           = "DESTROY"
+)
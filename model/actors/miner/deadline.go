@@ -0,0 +1,66 @@
+package miner
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+
+	"github.com/filecoin-project/lily/model"
+)
+
+// MinerDeadlineState captures a miner's current proving deadline status
+// at height, plus whether the miner has any live sectors at all - the
+// true active-miner signal under nv12+ "deadline toggling", which is not
+// the same as having nonzero power.
+type MinerDeadlineState struct {
+	Height               int64  `pg:",pk,notnull,use_zero"`
+	MinerID              string `pg:",pk,notnull"`
+	CurrentDeadlineIndex uint64 `pg:",notnull,use_zero"`
+	PeriodStart          int64  `pg:",notnull,use_zero"`
+	Challenge            int64  `pg:",notnull,use_zero"`
+	FaultCutoff          int64  `pg:",notnull,use_zero"`
+	LiveSectors          uint64 `pg:",notnull,use_zero"`
+	TotalSectors         uint64 `pg:",notnull,use_zero"`
+	FaultyPower          string `pg:",notnull"`
+	DisputableProofCount uint64 `pg:",notnull,use_zero"`
+	Active               bool   `pg:",notnull,use_zero"`
+}
+
+func (mds *MinerDeadlineState) AsModel() *MinerDeadlineState {
+	return mds
+}
+
+type MinerDeadlineStateList []*MinerDeadlineState
+
+func (ml MinerDeadlineStateList) Persist(ctx context.Context, s model.StorageBatch) error {
+	if len(ml) == 0 {
+		return nil
+	}
+	ctx, span := otel.Tracer("").Start(ctx, "MinerDeadlineStateList.Persist")
+	defer span.End()
+	return s.PersistModel(ctx, ml)
+}
+
+// MinerActiveTransition records a miner flipping active<->inactive
+// between two consecutive tipsets, letting downstream users compute the
+// true active miner set over time without rescanning every deadline.
+type MinerActiveTransition struct {
+	Height  int64  `pg:",pk,notnull,use_zero"`
+	MinerID string `pg:",pk,notnull"`
+	Active  bool   `pg:",notnull,use_zero"`
+}
+
+func (mat *MinerActiveTransition) AsModel() *MinerActiveTransition {
+	return mat
+}
+
+type MinerActiveTransitionList []*MinerActiveTransition
+
+func (ml MinerActiveTransitionList) Persist(ctx context.Context, s model.StorageBatch) error {
+	if len(ml) == 0 {
+		return nil
+	}
+	ctx, span := otel.Tracer("").Start(ctx, "MinerActiveTransitionList.Persist")
+	defer span.End()
+	return s.PersistModel(ctx, ml)
+}
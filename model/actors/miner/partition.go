@@ -0,0 +1,75 @@
+package miner
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+
+	"github.com/filecoin-project/lily/model"
+)
+
+// MinerPartition captures the full sector-state breakdown of a single
+// (deadline, partition) pair as observed at height, not just the sectors
+// PoStExtractor considers proven. Bitfields are persisted as their RLE+
+// binary encoding so downstream consumers can decode exactly the set lily
+// saw without lily having to maintain a denormalized sector-count schema
+// per actor version.
+type MinerPartition struct {
+	Height               int64  `pg:",pk,notnull,use_zero"`
+	MinerID              string `pg:",pk,notnull"`
+	DeadlineIdx          uint64 `pg:",pk,use_zero"`
+	PartitionIdx         uint64 `pg:",pk,use_zero"`
+	AllSectors           []byte `pg:",notnull"`
+	FaultySectors        []byte `pg:",notnull"`
+	RecoveringSectors    []byte `pg:",notnull"`
+	TerminatedSectors    []byte `pg:",notnull"`
+	UnprovenSectors      []byte `pg:",notnull"`
+	LiveSectorsCount     uint64 `pg:",use_zero"`
+	TotalSectorsCount    uint64 `pg:",use_zero"`
+	DisputableProofCount uint64 `pg:",use_zero"`
+}
+
+func (mp *MinerPartition) AsModel() *MinerPartition {
+	return mp
+}
+
+type MinerPartitionList []*MinerPartition
+
+func (ml MinerPartitionList) Persist(ctx context.Context, s model.StorageBatch) error {
+	if len(ml) == 0 {
+		return nil
+	}
+	ctx, span := otel.Tracer("").Start(ctx, "MinerPartitionList.Persist")
+	defer span.End()
+	return s.PersistModel(ctx, ml)
+}
+
+// MinerSectorEvent records a single sector-level event observed while
+// processing a SubmitWindowedPoSt message: a sector that was skipped by
+// the prover, or a sector that was marked recovered by the same message.
+type MinerSectorEvent struct {
+	Height   int64  `pg:",pk,notnull,use_zero"`
+	MinerID  string `pg:",pk,notnull"`
+	SectorID uint64 `pg:",pk,use_zero"`
+	Event    string `pg:",pk,notnull"`
+}
+
+func (mse *MinerSectorEvent) AsModel() *MinerSectorEvent {
+	return mse
+}
+
+type MinerSectorEventList []*MinerSectorEvent
+
+func (ml MinerSectorEventList) Persist(ctx context.Context, s model.StorageBatch) error {
+	if len(ml) == 0 {
+		return nil
+	}
+	ctx, span := otel.Tracer("").Start(ctx, "MinerSectorEventList.Persist")
+	defer span.End()
+	return s.PersistModel(ctx, ml)
+}
+
+const (
+	SectorEventSkipped   = "SKIPPED"
+	SectorEventRecovered = "RECOVERED"
+)
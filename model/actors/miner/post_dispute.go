@@ -0,0 +1,47 @@
+package miner
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+
+	"github.com/filecoin-project/lily/model"
+)
+
+// MinerSectorPostDispute captures the outcome of a successful
+// DisputeWindowedPoSt message, one row per sector that was optimistically
+// accepted as proven by PoStExtractor and is now known to have been
+// disputed. Joining this against MinerSectorPost lets downstream users
+// tell which optimistically-accepted PoSts were actually upheld.
+//
+// Penalty and Reward are always "0": the actual amounts are paid out via
+// internal sends on the dispute message, which lily does not yet trace,
+// so "0" here means "not measured", not "none paid". Treat both columns
+// as unpopulated until internal-send tracing lands for this method.
+type MinerSectorPostDispute struct {
+	Height            int64  `pg:",pk,notnull,use_zero"`
+	MinerID           string `pg:",pk,notnull"`
+	SectorID          uint64 `pg:",pk,use_zero"`
+	DisputeMessageCID string `pg:",pk,notnull"`
+	DisputedDeadline  uint64 `pg:",notnull,use_zero"`
+	DisputedPostIndex uint64 `pg:",notnull,use_zero"`
+	Disputer          string `pg:",notnull"`
+	ExitCode          int64  `pg:",notnull,use_zero"`
+	Penalty           string `pg:",notnull"`
+	Reward            string `pg:",notnull"`
+}
+
+func (msd *MinerSectorPostDispute) AsModel() *MinerSectorPostDispute {
+	return msd
+}
+
+type MinerSectorPostDisputeList []*MinerSectorPostDispute
+
+func (ml MinerSectorPostDisputeList) Persist(ctx context.Context, s model.StorageBatch) error {
+	if len(ml) == 0 {
+		return nil
+	}
+	ctx, span := otel.Tracer("").Start(ctx, "MinerSectorPostDisputeList.Persist")
+	defer span.End()
+	return s.PersistModel(ctx, ml)
+}
@@ -0,0 +1,151 @@
+package datacap
+
+import (
+	"testing"
+
+	"github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/filecoin-project/go-state-types/big"
+	"github.com/ipfs/go-cid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/filecoin-project/lily/chain/actors"
+	"github.com/filecoin-project/lily/chain/actors/builtin/datacap"
+	"github.com/filecoin-project/lotus/chain/actors/adt"
+)
+
+// fakeDatacapState is a minimal datacap.State fixture for exercising
+// diffAllowances' HAMT-diff logic without a real actor state/ADT store,
+// the same pattern tasks/actorstate/miner/post_dispute_test.go uses for
+// miner.Partition.
+type fakeDatacapState struct {
+	allowances []fakeAllowance
+}
+
+type fakeAllowance struct {
+	owner, operator address.Address
+	allowance       abi.TokenAmount
+}
+
+func (f *fakeDatacapState) ForEachAllowance(cb func(owner, operator address.Address, allowance abi.TokenAmount) error) error {
+	for _, a := range f.allowances {
+		if err := cb(a.owner, a.operator, a.allowance); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (f *fakeDatacapState) Code() cid.Cid                { panic("not implemented") }
+func (f *fakeDatacapState) ActorKey() string             { panic("not implemented") }
+func (f *fakeDatacapState) ActorVersion() actors.Version { panic("not implemented") }
+func (f *fakeDatacapState) Governor() (address.Address, error) {
+	panic("not implemented")
+}
+func (f *fakeDatacapState) VerifiedClients() (adt.Map, error) { panic("not implemented") }
+func (f *fakeDatacapState) VerifiedClientDataCap(address.Address) (bool, abi.StoragePower, error) {
+	panic("not implemented")
+}
+func (f *fakeDatacapState) VerifiedClientsMapBitWidth() int { panic("not implemented") }
+func (f *fakeDatacapState) VerifiedClientsMapHashFunction() func(input []byte) []byte {
+	panic("not implemented")
+}
+func (f *fakeDatacapState) ForEachClient(cb func(addr address.Address, dcap abi.StoragePower) error) error {
+	panic("not implemented")
+}
+func (f *fakeDatacapState) GetState() interface{} { panic("not implemented") }
+
+var _ datacap.State = (*fakeDatacapState)(nil)
+
+func mustIDAddress(t *testing.T, id uint64) address.Address {
+	t.Helper()
+	addr, err := address.NewIDAddress(id)
+	require.NoError(t, err)
+	return addr
+}
+
+func TestDiffAllowancesEmitsChangedAndNewRows(t *testing.T) {
+	owner := mustIDAddress(t, 100)
+	operator := mustIDAddress(t, 200)
+
+	prev := &fakeDatacapState{allowances: []fakeAllowance{
+		{owner: owner, operator: operator, allowance: big.NewInt(5)},
+	}}
+	cur := &fakeDatacapState{allowances: []fakeAllowance{
+		{owner: owner, operator: operator, allowance: big.NewInt(7)},
+	}}
+
+	rows, err := diffAllowances(10, prev, cur)
+	require.NoError(t, err)
+	require.Len(t, rows, 1)
+	require.Equal(t, owner.String(), rows[0].Owner)
+	require.Equal(t, operator.String(), rows[0].Operator)
+	require.Equal(t, big.NewInt(7).String(), rows[0].Allowance)
+}
+
+func TestDiffAllowancesSkipsUnchangedRows(t *testing.T) {
+	owner := mustIDAddress(t, 100)
+	operator := mustIDAddress(t, 200)
+
+	prev := &fakeDatacapState{allowances: []fakeAllowance{
+		{owner: owner, operator: operator, allowance: big.NewInt(5)},
+	}}
+	cur := &fakeDatacapState{allowances: []fakeAllowance{
+		{owner: owner, operator: operator, allowance: big.NewInt(5)},
+	}}
+
+	rows, err := diffAllowances(10, prev, cur)
+	require.NoError(t, err)
+	require.Empty(t, rows)
+}
+
+// TestDiffAllowancesEmitsZeroRowForRevokedAllowance guards the bug fixed
+// in chunk0-3's follow-up commit: a pair removed from the HAMT entirely
+// (revoked), rather than merely zeroed, must still produce a
+// zero-allowance row so a "latest allowance" read doesn't see the stale
+// pre-revoke amount forever.
+func TestDiffAllowancesEmitsZeroRowForRevokedAllowance(t *testing.T) {
+	owner := mustIDAddress(t, 100)
+	operator := mustIDAddress(t, 200)
+
+	prev := &fakeDatacapState{allowances: []fakeAllowance{
+		{owner: owner, operator: operator, allowance: big.NewInt(5)},
+	}}
+	cur := &fakeDatacapState{}
+
+	rows, err := diffAllowances(10, prev, cur)
+	require.NoError(t, err)
+	require.Len(t, rows, 1)
+	require.Equal(t, owner.String(), rows[0].Owner)
+	require.Equal(t, operator.String(), rows[0].Operator)
+	require.Equal(t, big.Zero().String(), rows[0].Allowance)
+}
+
+// TestDiffAllowancesDoesNotCollideOnUnseparatedKeys guards the
+// key-collision bug fixed in 0413a0a: owner=f01/operator=23 must never
+// be treated as the same pair as owner=f0/operator=123.
+func TestDiffAllowancesDoesNotCollideOnUnseparatedKeys(t *testing.T) {
+	ownerA, operatorA := mustIDAddress(t, 1), mustIDAddress(t, 23)
+	ownerB, operatorB := mustIDAddress(t, 0), mustIDAddress(t, 123)
+
+	prev := &fakeDatacapState{allowances: []fakeAllowance{
+		{owner: ownerA, operator: operatorA, allowance: big.NewInt(5)},
+	}}
+	cur := &fakeDatacapState{allowances: []fakeAllowance{
+		{owner: ownerB, operator: operatorB, allowance: big.NewInt(5)},
+	}}
+
+	rows, err := diffAllowances(10, prev, cur)
+	require.NoError(t, err)
+
+	// Both pairs must be reported: ownerB/operatorB is new (not a match
+	// for ownerA/operatorA), and ownerA/operatorA was revoked and needs
+	// its zero row.
+	require.Len(t, rows, 2)
+	byOwner := make(map[string]string, len(rows))
+	for _, r := range rows {
+		byOwner[r.Owner+"/"+r.Operator] = r.Allowance
+	}
+	require.Equal(t, big.NewInt(5).String(), byOwner[ownerB.String()+"/"+operatorB.String()])
+	require.Equal(t, big.Zero().String(), byOwner[ownerA.String()+"/"+operatorA.String()])
+}
@@ -0,0 +1,62 @@
+package datacap
+
+import (
+	"context"
+
+	logging "github.com/ipfs/go-log/v2"
+	"golang.org/x/xerrors"
+
+	"github.com/filecoin-project/lily/chain/actors/builtin/datacap"
+	"github.com/filecoin-project/lily/tasks/actorstate"
+)
+
+var log = logging.Logger("lily/tasks/datacap")
+
+// StateExtractionContext loads the current and, where available,
+// previous datacap actor state for a single tipset so extractors can
+// diff between them without each re-deriving the previous actor view.
+type StateExtractionContext struct {
+	CurrentState datacap.State
+	CurrentTs    actorstate.ActorInfo
+
+	PrevState datacap.State
+	PrevTs    actorstate.ActorInfo
+}
+
+func (s *StateExtractionContext) HasPreviousState() bool {
+	return s.PrevState != nil
+}
+
+func NewDatacapStateExtractionContext(ctx context.Context, a actorstate.ActorInfo, node actorstate.ActorStateAPI) (*StateExtractionContext, error) {
+	curActor, err := node.Actor(ctx, a.Address, a.Current.Key())
+	if err != nil {
+		return nil, xerrors.Errorf("loading current datacap actor: %w", err)
+	}
+	curState, err := datacap.Load(node.Store(), curActor)
+	if err != nil {
+		return nil, xerrors.Errorf("loading current datacap state: %w", err)
+	}
+
+	ec := &StateExtractionContext{
+		CurrentState: curState,
+		CurrentTs:    a,
+	}
+
+	// genesis has no parent state to diff against.
+	if a.Current.Height() == 0 {
+		return ec, nil
+	}
+
+	prevActor, err := node.Actor(ctx, a.Address, a.Executed.Key())
+	if err != nil {
+		return nil, xerrors.Errorf("loading previous datacap actor: %w", err)
+	}
+	prevState, err := datacap.Load(node.Store(), prevActor)
+	if err != nil {
+		return nil, xerrors.Errorf("loading previous datacap state: %w", err)
+	}
+	ec.PrevState = prevState
+	ec.PrevTs = a
+
+	return ec, nil
+}
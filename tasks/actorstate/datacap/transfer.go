@@ -0,0 +1,166 @@
+package datacap
+
+import (
+	"bytes"
+	"context"
+
+	datacap9 "github.com/filecoin-project/go-state-types/builtin/v9/datacap"
+	"go.opentelemetry.io/otel"
+	"go.uber.org/zap"
+	"golang.org/x/xerrors"
+
+	"github.com/filecoin-project/lily/chain/actors/builtin/datacap"
+	"github.com/filecoin-project/lily/lens"
+	"github.com/filecoin-project/lily/model"
+	datacapmodel "github.com/filecoin-project/lily/model/actors/datacap"
+	"github.com/filecoin-project/lily/tasks/actorstate"
+)
+
+// DatacapTransferExtractor parses executed messages sent to the v9
+// datacap actor's token methods (transfer, allowance management, mint,
+// burn) into a DatacapTokenEvent per message.
+type DatacapTransferExtractor struct{}
+
+func (DatacapTransferExtractor) Extract(ctx context.Context, a actorstate.ActorInfo, node actorstate.ActorStateAPI) (model.Persistable, error) {
+	log.Debugw("extract", zap.String("extractor", "DatacapTransferExtractor"), zap.Inline(a))
+	ctx, span := otel.Tracer("").Start(ctx, "DatacapTransferExtractor.Extract")
+	defer span.End()
+	if span.IsRecording() {
+		span.SetAttributes(a.Attributes()...)
+	}
+
+	tsMsgs, err := node.ExecutedAndBlockMessages(ctx, a.Current, a.Executed)
+	if err != nil {
+		return nil, xerrors.Errorf("getting executed and block messages: %w", err)
+	}
+
+	events := make(datacapmodel.DatacapTokenEventList, 0)
+	for _, msg := range tsMsgs.Executed {
+		if msg.Message.To != a.Address {
+			continue
+		}
+
+		event, err := tokenEventFromMessage(msg)
+		if err != nil {
+			return nil, xerrors.Errorf("parsing token event for %s: %w", msg.Cid, err)
+		}
+		if event == nil {
+			continue
+		}
+
+		event.Height = int64(a.Current.Height())
+		event.MessageCID = msg.Cid.String()
+		if msg.Receipt != nil {
+			event.ExitCode = int64(msg.Receipt.ExitCode)
+		}
+		events = append(events, event)
+	}
+	return events, nil
+}
+
+func tokenEventFromMessage(msg *lens.ExecutedMessage) (*datacapmodel.DatacapTokenEvent, error) {
+	reader := bytes.NewBuffer(msg.Message.Params)
+	from := msg.Message.From.String()
+
+	switch msg.Message.Method {
+	case datacap.Methods.Transfer:
+		var params datacap9.TransferParams
+		if err := params.UnmarshalCBOR(reader); err != nil {
+			return nil, err
+		}
+		return &datacapmodel.DatacapTokenEvent{
+			Event:  datacapmodel.DatacapEventTransfer,
+			From:   from,
+			To:     params.To.String(),
+			Amount: params.Amount.String(),
+		}, nil
+	case datacap.Methods.TransferFrom:
+		var params datacap9.TransferFromParams
+		if err := params.UnmarshalCBOR(reader); err != nil {
+			return nil, err
+		}
+		return &datacapmodel.DatacapTokenEvent{
+			Event:    datacapmodel.DatacapEventTransferFrom,
+			From:     params.From.String(),
+			To:       params.To.String(),
+			Operator: from,
+			Amount:   params.Amount.String(),
+		}, nil
+	case datacap.Methods.IncreaseAllowance:
+		var params datacap9.IncreaseAllowanceParams
+		if err := params.UnmarshalCBOR(reader); err != nil {
+			return nil, err
+		}
+		return &datacapmodel.DatacapTokenEvent{
+			Event:    datacapmodel.DatacapEventIncreaseAllowance,
+			From:     from,
+			Operator: params.Operator.String(),
+			Amount:   params.Increase.String(),
+		}, nil
+	case datacap.Methods.DecreaseAllowance:
+		var params datacap9.DecreaseAllowanceParams
+		if err := params.UnmarshalCBOR(reader); err != nil {
+			return nil, err
+		}
+		return &datacapmodel.DatacapTokenEvent{
+			Event:    datacapmodel.DatacapEventDecreaseAllowance,
+			From:     from,
+			Operator: params.Operator.String(),
+			Amount:   params.Decrease.String(),
+		}, nil
+	case datacap.Methods.RevokeAllowance:
+		var params datacap9.RevokeAllowanceParams
+		if err := params.UnmarshalCBOR(reader); err != nil {
+			return nil, err
+		}
+		return &datacapmodel.DatacapTokenEvent{
+			Event:    datacapmodel.DatacapEventRevokeAllowance,
+			From:     from,
+			Operator: params.Operator.String(),
+		}, nil
+	case datacap.Methods.Burn:
+		var params datacap9.BurnParams
+		if err := params.UnmarshalCBOR(reader); err != nil {
+			return nil, err
+		}
+		return &datacapmodel.DatacapTokenEvent{
+			Event:  datacapmodel.DatacapEventBurn,
+			From:   from,
+			Amount: params.Amount.String(),
+		}, nil
+	case datacap.Methods.BurnFrom:
+		var params datacap9.BurnFromParams
+		if err := params.UnmarshalCBOR(reader); err != nil {
+			return nil, err
+		}
+		return &datacapmodel.DatacapTokenEvent{
+			Event:    datacapmodel.DatacapEventBurnFrom,
+			From:     params.Owner.String(),
+			Operator: from,
+			Amount:   params.Amount.String(),
+		}, nil
+	case datacap.Methods.Mint:
+		var params datacap9.MintParams
+		if err := params.UnmarshalCBOR(reader); err != nil {
+			return nil, err
+		}
+		return &datacapmodel.DatacapTokenEvent{
+			Event:  datacapmodel.DatacapEventMint,
+			From:   from,
+			To:     params.To.String(),
+			Amount: params.Amount.String(),
+		}, nil
+	case datacap.Methods.Destroy:
+		var params datacap9.DestroyParams
+		if err := params.UnmarshalCBOR(reader); err != nil {
+			return nil, err
+		}
+		return &datacapmodel.DatacapTokenEvent{
+			Event:  datacapmodel.DatacapEventDestroy,
+			From:   params.Owner.String(),
+			Amount: params.Amount.String(),
+		}, nil
+	default:
+		return nil, nil
+	}
+}
@@ -0,0 +1,131 @@
+package miner
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.uber.org/zap"
+	"golang.org/x/xerrors"
+
+	"github.com/filecoin-project/lily/chain/actors/builtin/miner"
+	"github.com/filecoin-project/lily/model"
+	minermodel "github.com/filecoin-project/lily/model/actors/miner"
+	"github.com/filecoin-project/lily/tasks/actorstate"
+)
+
+// MinerDeadlineStateExtractor emits a miner's current proving deadline
+// status for the tipset and, when the miner's active-set membership
+// changes, an event row recording the active<->inactive transition.
+//
+// Active here mirrors the nv12+ "deadline toggling" logic that removes
+// miners with no live sectors from cron: it is not the same as having
+// nonzero power, since a miner that has terminated every sector is still
+// an alive actor, just one with nothing left to prove.
+type MinerDeadlineStateExtractor struct{}
+
+func totalLiveSectors(state miner.State) (uint64, error) {
+	var total uint64
+	if err := state.ForEachDeadline(func(_ uint64, dl miner.Deadline) error {
+		live, err := dl.LiveSectors()
+		if err != nil {
+			return xerrors.Errorf("live sectors: %w", err)
+		}
+		total += live
+		return nil
+	}); err != nil {
+		return 0, xerrors.Errorf("foreach deadline: %w", err)
+	}
+	return total, nil
+}
+
+// activeTransition reports the MinerActiveTransition row implied by a
+// miner's active-set membership moving from prevActive to curActive, or
+// nil if it didn't change.
+func activeTransition(height int64, minerID string, prevActive, curActive bool) *minermodel.MinerActiveTransition {
+	if prevActive == curActive {
+		return nil
+	}
+	return &minermodel.MinerActiveTransition{
+		Height:  height,
+		MinerID: minerID,
+		Active:  curActive,
+	}
+}
+
+func (MinerDeadlineStateExtractor) Extract(ctx context.Context, a actorstate.ActorInfo, node actorstate.ActorStateAPI) (model.Persistable, error) {
+	log.Debugw("extract", zap.String("extractor", "MinerDeadlineStateExtractor"), zap.Inline(a))
+	ctx, span := otel.Tracer("").Start(ctx, "MinerDeadlineStateExtractor.Extract")
+	defer span.End()
+	if span.IsRecording() {
+		span.SetAttributes(a.Attributes()...)
+	}
+
+	ec, err := NewMinerStateExtractionContext(ctx, a, node)
+	if err != nil {
+		return nil, xerrors.Errorf("creating miner state extraction context: %w", err)
+	}
+
+	addr := a.Address.String()
+	height := int64(a.Current.Height())
+
+	curLive, err := totalLiveSectors(ec.CurrentState)
+	if err != nil {
+		return nil, xerrors.Errorf("current live sectors: %w", err)
+	}
+	curActive := curLive > 0
+
+	info, err := ec.CurrentState.DeadlineInfo(a.Current.Height())
+	if err != nil {
+		return nil, xerrors.Errorf("deadline info: %w", err)
+	}
+	dl, err := ec.CurrentState.LoadDeadline(info.Index)
+	if err != nil {
+		return nil, xerrors.Errorf("load current deadline: %w", err)
+	}
+	disputable, err := dl.DisputableProofCount()
+	if err != nil {
+		return nil, xerrors.Errorf("disputable proof count: %w", err)
+	}
+	live, err := dl.LiveSectors()
+	if err != nil {
+		return nil, xerrors.Errorf("live sectors: %w", err)
+	}
+	total, err := dl.TotalSectors()
+	if err != nil {
+		return nil, xerrors.Errorf("total sectors: %w", err)
+	}
+	faultyPower, err := dl.FaultyPower()
+	if err != nil {
+		return nil, xerrors.Errorf("faulty power: %w", err)
+	}
+
+	out := model.PersistableList{
+		&minermodel.MinerDeadlineState{
+			Height:               height,
+			MinerID:              addr,
+			CurrentDeadlineIndex: info.Index,
+			PeriodStart:          int64(info.PeriodStart),
+			Challenge:            int64(info.Challenge),
+			FaultCutoff:          int64(info.FaultCutoff),
+			LiveSectors:          live,
+			TotalSectors:         total,
+			FaultyPower:          faultyPower.String(),
+			DisputableProofCount: disputable,
+			Active:               curActive,
+		},
+	}
+
+	if ec.HasPreviousState() {
+		prevLive, err := totalLiveSectors(ec.PrevState)
+		if err != nil {
+			return nil, xerrors.Errorf("previous live sectors: %w", err)
+		}
+		prevActive := prevLive > 0
+
+		if t := activeTransition(height, addr, prevActive, curActive); t != nil {
+			out = append(out, minermodel.MinerActiveTransitionList{t})
+		}
+	}
+
+	return out, nil
+}
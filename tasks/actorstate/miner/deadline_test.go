@@ -0,0 +1,114 @@
+package miner
+
+import (
+	"testing"
+
+	"github.com/filecoin-project/go-bitfield"
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/filecoin-project/go-state-types/dline"
+	"github.com/ipfs/go-cid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/filecoin-project/lily/chain/actors"
+	"github.com/filecoin-project/lily/chain/actors/builtin/miner"
+)
+
+// fakeDeadlineLiveSectors is a minimal miner.Deadline fixture that only
+// implements LiveSectors, the one method totalLiveSectors calls.
+type fakeDeadlineLiveSectors struct {
+	live uint64
+}
+
+func (d *fakeDeadlineLiveSectors) DisputableProofCount() (uint64, error) { panic("not implemented") }
+func (d *fakeDeadlineLiveSectors) LiveSectors() (uint64, error)          { return d.live, nil }
+func (d *fakeDeadlineLiveSectors) TotalSectors() (uint64, error)         { panic("not implemented") }
+func (d *fakeDeadlineLiveSectors) FaultyPower() (abi.StoragePower, error) {
+	panic("not implemented")
+}
+func (d *fakeDeadlineLiveSectors) LoadPartition(idx uint64) (miner.Partition, error) {
+	panic("not implemented")
+}
+func (d *fakeDeadlineLiveSectors) ForEachPartition(cb func(idx uint64, p miner.Partition) error) error {
+	panic("not implemented")
+}
+func (d *fakeDeadlineLiveSectors) LoadProofPartitions(postIndex uint64) (bitfield.BitField, error) {
+	panic("not implemented")
+}
+
+var _ miner.Deadline = (*fakeDeadlineLiveSectors)(nil)
+
+// fakeMinerState is a minimal miner.State fixture whose ForEachDeadline
+// walks a fixed set of deadlines, for exercising totalLiveSectors without
+// a real actor state/ADT store.
+type fakeMinerState struct {
+	deadlines []*fakeDeadlineLiveSectors
+}
+
+func (s *fakeMinerState) Code() cid.Cid                { panic("not implemented") }
+func (s *fakeMinerState) ActorKey() string             { panic("not implemented") }
+func (s *fakeMinerState) ActorVersion() actors.Version { panic("not implemented") }
+func (s *fakeMinerState) DeadlineInfo(epoch abi.ChainEpoch) (*dline.Info, error) {
+	panic("not implemented")
+}
+func (s *fakeMinerState) LoadDeadline(idx uint64) (miner.Deadline, error) {
+	panic("not implemented")
+}
+func (s *fakeMinerState) ForEachDeadline(cb func(idx uint64, dl miner.Deadline) error) error {
+	for idx, dl := range s.deadlines {
+		if err := cb(uint64(idx), dl); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+func (s *fakeMinerState) DecodeSubmitWindowedPoStParams(raw []byte) (miner.SubmitWindowedPoStParams, error) {
+	panic("not implemented")
+}
+func (s *fakeMinerState) GetState() interface{} { panic("not implemented") }
+
+var _ miner.State = (*fakeMinerState)(nil)
+
+func TestTotalLiveSectorsSumsAcrossDeadlines(t *testing.T) {
+	state := &fakeMinerState{deadlines: []*fakeDeadlineLiveSectors{
+		{live: 3},
+		{live: 0},
+		{live: 5},
+	}}
+
+	total, err := totalLiveSectors(state)
+	require.NoError(t, err)
+	require.Equal(t, uint64(8), total)
+}
+
+func TestTotalLiveSectorsZeroWhenNoDeadlinesHaveLiveSectors(t *testing.T) {
+	state := &fakeMinerState{deadlines: []*fakeDeadlineLiveSectors{
+		{live: 0},
+		{live: 0},
+	}}
+
+	total, err := totalLiveSectors(state)
+	require.NoError(t, err)
+	require.Equal(t, uint64(0), total)
+}
+
+func TestActiveTransitionNilWhenUnchanged(t *testing.T) {
+	require.Nil(t, activeTransition(10, "f0100", true, true))
+	require.Nil(t, activeTransition(10, "f0100", false, false))
+}
+
+func TestActiveTransitionReportsBecomingInactive(t *testing.T) {
+	// A miner whose last live sector terminates drops out of the
+	// active set - this is the nv12+ "deadline toggling" transition the
+	// model's doc comment describes.
+	transition := activeTransition(10, "f0100", true, false)
+	require.NotNil(t, transition)
+	require.Equal(t, int64(10), transition.Height)
+	require.Equal(t, "f0100", transition.MinerID)
+	require.False(t, transition.Active)
+}
+
+func TestActiveTransitionReportsBecomingActive(t *testing.T) {
+	transition := activeTransition(10, "f0100", false, true)
+	require.NotNil(t, transition)
+	require.True(t, transition.Active)
+}
@@ -0,0 +1,256 @@
+package miner
+
+import (
+	"context"
+
+	"github.com/filecoin-project/go-bitfield"
+	rlepluslazy "github.com/filecoin-project/go-bitfield/rle"
+	"github.com/filecoin-project/go-state-types/abi"
+	"go.opentelemetry.io/otel"
+	"go.uber.org/zap"
+	"golang.org/x/xerrors"
+
+	"github.com/filecoin-project/lily/chain/actors/builtin/miner"
+	"github.com/filecoin-project/lily/lens"
+	"github.com/filecoin-project/lily/model"
+	minermodel "github.com/filecoin-project/lily/model/actors/miner"
+	"github.com/filecoin-project/lily/tasks/actorstate"
+)
+
+// PartitionExtractor emits the full per-partition sector breakdown (not
+// just the proven set PoStExtractor records) alongside the skipped and
+// recovered sector events observed while processing SubmitWindowedPoSt
+// messages, so analytics can reconstruct proving-set churn without
+// scanning every sector info.
+type PartitionExtractor struct{}
+
+func encodeBitfield(bf bitfield.BitField) ([]byte, error) {
+	rit, err := bf.RunIterator()
+	if err != nil {
+		return nil, xerrors.Errorf("bitfield run iterator: %w", err)
+	}
+	enc, err := rlepluslazy.EncodeRuns(rit, nil)
+	if err != nil {
+		return nil, xerrors.Errorf("encode bitfield runs: %w", err)
+	}
+	return enc, nil
+}
+
+// partitionSnapshot builds the full per-partition sector breakdown row for
+// p, the state of partition pIdx in deadline dlIdx at height.
+func partitionSnapshot(height int64, minerID string, dlIdx, pIdx uint64, p miner.Partition, live, total, disputable uint64) (*minermodel.MinerPartition, error) {
+	all, err := p.AllSectors()
+	if err != nil {
+		return nil, xerrors.Errorf("all sectors: %w", err)
+	}
+	faults, err := p.FaultySectors()
+	if err != nil {
+		return nil, xerrors.Errorf("faulty sectors: %w", err)
+	}
+	recovering, err := p.RecoveringSectors()
+	if err != nil {
+		return nil, xerrors.Errorf("recovering sectors: %w", err)
+	}
+	terminated, err := p.TerminatedSectors()
+	if err != nil {
+		return nil, xerrors.Errorf("terminated sectors: %w", err)
+	}
+	unproven, err := p.UnprovenSectors()
+	if err != nil {
+		return nil, xerrors.Errorf("unproven sectors: %w", err)
+	}
+
+	allEnc, err := encodeBitfield(all)
+	if err != nil {
+		return nil, err
+	}
+	faultsEnc, err := encodeBitfield(faults)
+	if err != nil {
+		return nil, err
+	}
+	recoveringEnc, err := encodeBitfield(recovering)
+	if err != nil {
+		return nil, err
+	}
+	terminatedEnc, err := encodeBitfield(terminated)
+	if err != nil {
+		return nil, err
+	}
+	unprovenEnc, err := encodeBitfield(unproven)
+	if err != nil {
+		return nil, err
+	}
+
+	return &minermodel.MinerPartition{
+		Height:               height,
+		MinerID:              minerID,
+		DeadlineIdx:          dlIdx,
+		PartitionIdx:         pIdx,
+		AllSectors:           allEnc,
+		FaultySectors:        faultsEnc,
+		RecoveringSectors:    recoveringEnc,
+		TerminatedSectors:    terminatedEnc,
+		UnprovenSectors:      unprovenEnc,
+		LiveSectorsCount:     live,
+		TotalSectorsCount:    total,
+		DisputableProofCount: disputable,
+	}, nil
+}
+
+// postPartitionEvents diffs a single PoSt-submitted partition's skipped
+// sectors against prev (that partition's state before the PoSt landed) and
+// returns the skipped/recovered sector events it implies: every skipped
+// sector is an event in its own right, and any sector prev had recovering
+// that wasn't skipped this time around made it back into the proven set,
+// i.e. recovered.
+func postPartitionEvents(height int64, minerID string, prev miner.Partition, pp miner.PoStPartition) (minermodel.MinerSectorEventList, error) {
+	events := make(minermodel.MinerSectorEventList, 0)
+
+	if err := pp.Skipped.ForEach(func(sector uint64) error {
+		events = append(events, &minermodel.MinerSectorEvent{
+			Height:   height,
+			MinerID:  minerID,
+			SectorID: sector,
+			Event:    minermodel.SectorEventSkipped,
+		})
+		return nil
+	}); err != nil {
+		return nil, xerrors.Errorf("foreach skipped: %w", err)
+	}
+
+	recovering, err := prev.RecoveringSectors()
+	if err != nil {
+		return nil, xerrors.Errorf("recovering sectors: %w", err)
+	}
+	recoveredThisPost, err := bitfield.SubtractBitField(recovering, pp.Skipped)
+	if err != nil {
+		return nil, xerrors.Errorf("subtract skipped from recovering: %w", err)
+	}
+	if err := recoveredThisPost.ForEach(func(sector uint64) error {
+		events = append(events, &minermodel.MinerSectorEvent{
+			Height:   height,
+			MinerID:  minerID,
+			SectorID: sector,
+			Event:    minermodel.SectorEventRecovered,
+		})
+		return nil
+	}); err != nil {
+		return nil, xerrors.Errorf("foreach recovered: %w", err)
+	}
+
+	return events, nil
+}
+
+func (PartitionExtractor) Extract(ctx context.Context, a actorstate.ActorInfo, node actorstate.ActorStateAPI) (model.Persistable, error) {
+	log.Debugw("extract", zap.String("extractor", "PartitionExtractor"), zap.Inline(a))
+	ctx, span := otel.Tracer("").Start(ctx, "PartitionExtractor.Extract")
+	defer span.End()
+	if span.IsRecording() {
+		span.SetAttributes(a.Attributes()...)
+	}
+
+	ec, err := NewMinerStateExtractionContext(ctx, a, node)
+	if err != nil {
+		return nil, xerrors.Errorf("creating miner state extraction context: %w", err)
+	}
+
+	addr := a.Address.String()
+	partitions := make(minermodel.MinerPartitionList, 0)
+
+	if err := ec.CurrentState.ForEachDeadline(func(dlIdx uint64, dl miner.Deadline) error {
+		disputable, err := dl.DisputableProofCount()
+		if err != nil {
+			return xerrors.Errorf("disputable proof count: %w", err)
+		}
+		live, err := dl.LiveSectors()
+		if err != nil {
+			return xerrors.Errorf("live sectors: %w", err)
+		}
+		total, err := dl.TotalSectors()
+		if err != nil {
+			return xerrors.Errorf("total sectors: %w", err)
+		}
+
+		return dl.ForEachPartition(func(pIdx uint64, p miner.Partition) error {
+			row, err := partitionSnapshot(int64(a.Current.Height()), addr, dlIdx, pIdx, p, live, total, disputable)
+			if err != nil {
+				return err
+			}
+			partitions = append(partitions, row)
+			return nil
+		})
+	}); err != nil {
+		return nil, xerrors.Errorf("foreach deadline: %w", err)
+	}
+
+	events := make(minermodel.MinerSectorEventList, 0)
+	// short circuit genesis state, no PoSt messages in genesis blocks, and
+	// we need the previous partition state to diagnose skipped/recovered
+	// sectors against.
+	if ec.HasPreviousState() {
+		var prevPartitions map[uint64]miner.Partition
+		loadPrevPartitions := func(epoch int64) (map[uint64]miner.Partition, error) {
+			info, err := ec.PrevState.DeadlineInfo(abi.ChainEpoch(epoch))
+			if err != nil {
+				return nil, xerrors.Errorf("deadline info: %w", err)
+			}
+			dline, err := ec.PrevState.LoadDeadline(info.Index)
+			if err != nil {
+				return nil, xerrors.Errorf("load deadline: %w", err)
+			}
+			pmap := make(map[uint64]miner.Partition)
+			if err := dline.ForEachPartition(func(idx uint64, p miner.Partition) error {
+				pmap[idx] = p
+				return nil
+			}); err != nil {
+				return nil, xerrors.Errorf("foreach partition: %w", err)
+			}
+			return pmap, nil
+		}
+
+		processPostMsg := func(msg *lens.ExecutedMessage) error {
+			if msg.Receipt == nil || msg.Receipt.ExitCode.IsError() {
+				return nil
+			}
+			params, err := ec.PrevState.DecodeSubmitWindowedPoStParams(msg.Message.Params)
+			if err != nil {
+				return xerrors.Errorf("decode post params: %w", err)
+			}
+
+			if prevPartitions == nil {
+				prevPartitions, err = loadPrevPartitions(int64(ec.PrevTs.Height()))
+				if err != nil {
+					return xerrors.Errorf("load partitions: %w", err)
+				}
+			}
+
+			for _, pp := range params.Partitions {
+				prev, ok := prevPartitions[pp.Index]
+				if !ok {
+					continue
+				}
+
+				postEvents, err := postPartitionEvents(int64(ec.PrevTs.Height()), addr, prev, pp)
+				if err != nil {
+					return xerrors.Errorf("post partition events: %w", err)
+				}
+				events = append(events, postEvents...)
+			}
+			return nil
+		}
+
+		tsMsgs, err := node.ExecutedAndBlockMessages(ctx, a.Current, a.Executed)
+		if err != nil {
+			return nil, xerrors.Errorf("getting executed and block messages: %w", err)
+		}
+		for _, msg := range tsMsgs.Executed {
+			if msg.Message.To == a.Address && msg.Message.Method == 5 /* miner.SubmitWindowedPoSt */ {
+				if err := processPostMsg(msg); err != nil {
+					return nil, xerrors.Errorf("process post msg: %w", err)
+				}
+			}
+		}
+	}
+
+	return model.PersistableList{partitions, events}, nil
+}
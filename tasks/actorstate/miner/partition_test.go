@@ -0,0 +1,90 @@
+package miner
+
+import (
+	"testing"
+
+	"github.com/filecoin-project/go-bitfield"
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/stretchr/testify/require"
+
+	"github.com/filecoin-project/lily/chain/actors/builtin/miner"
+	minermodel "github.com/filecoin-project/lily/model/actors/miner"
+)
+
+// fakeFullPartition is a miner.Partition fixture with a settable
+// RecoveringSectors, unlike fakePartition in post_dispute_test.go which
+// hardcodes it empty - postPartitionEvents needs a real recovering set to
+// diff against.
+type fakeFullPartition struct {
+	all, faults, recovering, terminated, unproven bitfield.BitField
+}
+
+func (p *fakeFullPartition) AllSectors() (bitfield.BitField, error)    { return p.all, nil }
+func (p *fakeFullPartition) FaultySectors() (bitfield.BitField, error) { return p.faults, nil }
+func (p *fakeFullPartition) RecoveringSectors() (bitfield.BitField, error) {
+	return p.recovering, nil
+}
+func (p *fakeFullPartition) TerminatedSectors() (bitfield.BitField, error) { return p.terminated, nil }
+func (p *fakeFullPartition) UnprovenSectors() (bitfield.BitField, error)   { return p.unproven, nil }
+
+var _ miner.Partition = (*fakeFullPartition)(nil)
+
+func mustBitfieldSet(t *testing.T, bf bitfield.BitField) []uint64 {
+	t.Helper()
+	sectors, err := bf.All(abi.MaxSectorNumber)
+	require.NoError(t, err)
+	return sectors
+}
+
+func TestPartitionSnapshotEncodesSectorSets(t *testing.T) {
+	p := &fakeFullPartition{
+		all:        bitfield.NewFromSet([]uint64{1, 2, 3, 4, 5}),
+		faults:     bitfield.NewFromSet([]uint64{2}),
+		recovering: bitfield.NewFromSet([]uint64{2}),
+		terminated: bitfield.NewFromSet([]uint64{3}),
+		unproven:   bitfield.NewFromSet([]uint64{4}),
+	}
+
+	row, err := partitionSnapshot(10, "f0100", 1, 2, p, 5, 5, 1)
+	require.NoError(t, err)
+
+	require.Equal(t, int64(10), row.Height)
+	require.Equal(t, "f0100", row.MinerID)
+	require.Equal(t, uint64(1), row.DeadlineIdx)
+	require.Equal(t, uint64(2), row.PartitionIdx)
+	require.Equal(t, uint64(5), row.LiveSectorsCount)
+	require.Equal(t, uint64(5), row.TotalSectorsCount)
+	require.Equal(t, uint64(1), row.DisputableProofCount)
+
+	decoded, err := bitfield.NewFromBytes(row.FaultySectors)
+	require.NoError(t, err)
+	require.Equal(t, []uint64{2}, mustBitfieldSet(t, decoded))
+
+	decoded, err = bitfield.NewFromBytes(row.AllSectors)
+	require.NoError(t, err)
+	require.Equal(t, []uint64{1, 2, 3, 4, 5}, mustBitfieldSet(t, decoded))
+}
+
+func TestPostPartitionEventsEmitsSkippedAndRecovered(t *testing.T) {
+	// Partition previously had 5 and 6 recovering; this PoSt skips 5
+	// (still faulty/unrecovered) but not 6 (made it back in), so only 6
+	// should be reported recovered, and only 5 skipped.
+	prev := &fakeFullPartition{
+		recovering: bitfield.NewFromSet([]uint64{5, 6}),
+	}
+	pp := miner.PoStPartition{
+		Index:   2,
+		Skipped: bitfield.NewFromSet([]uint64{5}),
+	}
+
+	events, err := postPartitionEvents(10, "f0100", prev, pp)
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+
+	byEvent := make(map[string]uint64, len(events))
+	for _, e := range events {
+		byEvent[e.Event] = e.SectorID
+	}
+	require.Equal(t, uint64(5), byEvent[minermodel.SectorEventSkipped])
+	require.Equal(t, uint64(6), byEvent[minermodel.SectorEventRecovered])
+}
@@ -64,12 +64,15 @@ func (PoStExtractor) Extract(ctx context.Context, a actorstate.ActorInfo, node a
 		if msg.Receipt == nil || msg.Receipt.ExitCode.IsError() {
 			return nil
 		}
-		params := miner.SubmitWindowedPoStParams{}
-		if err := params.UnmarshalCBOR(bytes.NewBuffer(msg.Message.Params)); err != nil {
-			return xerrors.Errorf("unmarshal post params: %w", err)
+		// decode through the actor-version-specific layout rather than a
+		// single hard-coded params type, since the CBOR shape of
+		// SubmitWindowedPoStParams has changed across actor versions
+		// even though the method number has not.
+		params, err := ec.PrevState.DecodeSubmitWindowedPoStParams(msg.Message.Params)
+		if err != nil {
+			return xerrors.Errorf("decode post params: %w", err)
 		}
 
-		var err error
 		// use previous miner state and tipset state since we are using parent messages
 		if partitions == nil {
 			partitions, err = loadPartitions(ec.PrevState, ec.PrevTs.Height())
@@ -79,7 +82,11 @@ func (PoStExtractor) Extract(ctx context.Context, a actorstate.ActorInfo, node a
 		}
 
 		for _, p := range params.Partitions {
-			all, err := partitions[p.Index].AllSectors()
+			part, ok := partitions[p.Index]
+			if !ok {
+				continue
+			}
+			all, err := part.AllSectors()
 			if err != nil {
 				return xerrors.Errorf("all sectors: %w", err)
 			}
@@ -120,4 +127,4 @@ func (PoStExtractor) Extract(ctx context.Context, a actorstate.ActorInfo, node a
 		}
 	}
 	return posts, nil
-}
\ No newline at end of file
+}
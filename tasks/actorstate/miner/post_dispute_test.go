@@ -0,0 +1,108 @@
+package miner
+
+import (
+	"testing"
+
+	"github.com/filecoin-project/go-bitfield"
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/stretchr/testify/require"
+
+	"github.com/filecoin-project/lily/chain/actors/builtin/miner"
+)
+
+// fakePartition is a minimal miner.Partition fixture for exercising
+// provenSectors' bitfield math without a real ADT store.
+type fakePartition struct {
+	all, faults, terminated, unproven bitfield.BitField
+}
+
+func (p *fakePartition) AllSectors() (bitfield.BitField, error)    { return p.all, nil }
+func (p *fakePartition) FaultySectors() (bitfield.BitField, error) { return p.faults, nil }
+func (p *fakePartition) RecoveringSectors() (bitfield.BitField, error) {
+	return bitfield.NewFromSet(nil), nil
+}
+func (p *fakePartition) TerminatedSectors() (bitfield.BitField, error) { return p.terminated, nil }
+func (p *fakePartition) UnprovenSectors() (bitfield.BitField, error)   { return p.unproven, nil }
+
+var _ miner.Partition = (*fakePartition)(nil)
+
+// fakeDisputeDeadline is a minimal miner.Deadline fixture for exercising
+// disputedSectors' partition scoping: LoadProofPartitions reports a fixed
+// set of partitions regardless of which postIndex is asked for, and
+// LoadPartition serves them from a fixed map.
+type fakeDisputeDeadline struct {
+	partitions      map[uint64]miner.Partition
+	proofPartitions bitfield.BitField
+}
+
+func (d *fakeDisputeDeadline) DisputableProofCount() (uint64, error) { panic("not implemented") }
+func (d *fakeDisputeDeadline) LiveSectors() (uint64, error)          { panic("not implemented") }
+func (d *fakeDisputeDeadline) TotalSectors() (uint64, error)         { panic("not implemented") }
+func (d *fakeDisputeDeadline) FaultyPower() (abi.StoragePower, error) {
+	panic("not implemented")
+}
+func (d *fakeDisputeDeadline) LoadPartition(idx uint64) (miner.Partition, error) {
+	return d.partitions[idx], nil
+}
+func (d *fakeDisputeDeadline) ForEachPartition(cb func(idx uint64, p miner.Partition) error) error {
+	panic("not implemented")
+}
+func (d *fakeDisputeDeadline) LoadProofPartitions(postIndex uint64) (bitfield.BitField, error) {
+	return d.proofPartitions, nil
+}
+
+var _ miner.Deadline = (*fakeDisputeDeadline)(nil)
+
+func TestProvenSectors(t *testing.T) {
+	// Single-partition fixture: partition with sectors 1-4, where 2 is
+	// faulty (e.g. skipped by the prover), 3 is terminated, and 4 hasn't
+	// reached its first proof yet. Only 1 was actually proven.
+	p := &fakePartition{
+		all:        bitfield.NewFromSet([]uint64{1, 2, 3, 4}),
+		faults:     bitfield.NewFromSet([]uint64{2}),
+		terminated: bitfield.NewFromSet([]uint64{3}),
+		unproven:   bitfield.NewFromSet([]uint64{4}),
+	}
+
+	proven, err := provenSectors(p)
+	require.NoError(t, err)
+
+	got, err := proven.All(abi.MaxSectorNumber)
+	require.NoError(t, err)
+	require.Equal(t, []uint64{1}, got)
+}
+
+// TestDisputedSectorsExcludesUntouchedPartition guards the bug fixed in
+// 9a053a8: a dispute against one optimistic PoSt submission must only
+// invalidate the partitions that submission covered, not every partition
+// in the deadline. Partition 1 here is fully healthy and was never part
+// of the disputed submission, so none of its sectors may appear in the
+// result even though it has its own "proven" sectors.
+func TestDisputedSectorsExcludesUntouchedPartition(t *testing.T) {
+	disputedPartition := &fakePartition{
+		all:        bitfield.NewFromSet([]uint64{1, 2, 3}),
+		faults:     bitfield.NewFromSet([]uint64{2}),
+		terminated: bitfield.NewFromSet(nil),
+		unproven:   bitfield.NewFromSet(nil),
+	}
+	untouchedPartition := &fakePartition{
+		all:        bitfield.NewFromSet([]uint64{10, 11}),
+		faults:     bitfield.NewFromSet(nil),
+		terminated: bitfield.NewFromSet(nil),
+		unproven:   bitfield.NewFromSet(nil),
+	}
+	dline := &fakeDisputeDeadline{
+		partitions: map[uint64]miner.Partition{
+			0: disputedPartition,
+			1: untouchedPartition,
+		},
+		proofPartitions: bitfield.NewFromSet([]uint64{0}),
+	}
+
+	disputed, err := disputedSectors(dline, 0)
+	require.NoError(t, err)
+
+	got, err := disputed.All(abi.MaxSectorNumber)
+	require.NoError(t, err)
+	require.Equal(t, []uint64{1, 3}, got)
+}